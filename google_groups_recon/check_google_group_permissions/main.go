@@ -0,0 +1,854 @@
+// check_google_group_permissions analyzes Google Groups to determine their permission
+// settings and identify which groups are publicly accessible. This tool is useful for
+// security assessments to find groups with overly permissive settings.
+//
+// The tool reads Google Groups URLs from stdin and checks each group's /about page
+// to determine:
+//   - Whether the group is public (accessible without authentication)
+//   - Whether anyone can view conversations
+//   - Whether anyone can post messages
+//   - Whether anyone can join the group
+//
+// Usage:
+//
+//	cat urls.txt | ./check_google_group_permissions                  # find public groups
+//	cat urls.txt | ./check_google_group_permissions -verbose         # show all permission details
+//	cat urls.txt | ./check_google_group_permissions -require-post    # only groups where anyone can post
+//
+// Output:
+//   - stdout: URLs of groups matching the criteria (public and accessible)
+//   - stderr: Error messages, rejected groups, and verbose permission details
+//
+// Rate limiting: -concurrency workers (default 8) share a token-bucket
+// limiter capped at -rate requests per second (default 5). The limiter
+// adapts to the server's responses: a 429 or 503 halves the shared rate and
+// pauses new requests until the response's Retry-After elapses, while
+// sustained successful responses gradually restore the rate back up to the
+// ceiling.
+//
+// With -resume file.state, the tool records every URL it finishes checking,
+// so a run interrupted partway through can be restarted with the same
+// command and will skip URLs already processed.
+//
+// With -db path.sqlite, the tool reads URLs from the shared recon store's
+// urls table instead of stdin, and records a permissions snapshot per group
+// instead of printing matching URLs to stdout. Pass -max-age alongside -db
+// to skip groups whose most recent snapshot is still fresh, for incremental
+// scans.
+//
+// With -output json|jsonl|csv, the tool prints the fully populated
+// GroupPermissions for every group it checks, instead of just the URLs of
+// groups matching the open-access criteria.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/time/rate"
+
+	"github.com/spaceraccoon/vibe-sec-tools/google_groups_recon/store"
+)
+
+const (
+	// timeout is the maximum time to wait for an HTTP response.
+	timeout = 10 * time.Second
+	// maxAttempts bounds how many times a single URL is retried after being rate limited.
+	maxAttempts = 5
+)
+
+// GroupPermissions represents the permission settings discovered on a
+// Google Group's /about page.
+type GroupPermissions struct {
+	IsPublic    bool `json:"is_public"`    // True if the group is publicly accessible without login
+	CanView     bool `json:"can_view"`     // True if "Anyone on the web" can view conversations
+	CanPost     bool `json:"can_post"`     // True if "Anyone on the web" can post messages
+	CanJoin     bool `json:"can_join"`     // True if "Anyone on the web" can join the group
+	RequireAuth bool `json:"require_auth"` // True if the group requires authentication to access
+
+	WhoCanContactOwner   string `json:"who_can_contact_owner"`   // Who can email the group's owners directly
+	WhoCanSeeMembers     string `json:"who_can_see_members"`     // Who can view the member list
+	WhoCanModerate       string `json:"who_can_moderate"`        // Who can moderate content and members
+	AllowExternalMembers string `json:"allow_external_members"`  // Whether members outside the domain may join
+	MessageModeration    string `json:"message_moderation"`      // Whether posts are moderated before appearing
+	ArchivePolicy        string `json:"archive_policy"`          // How long message history is retained and to whom it's visible
+}
+
+// permissionRecord is a single group's permission check result, used for
+// -output json/jsonl/csv reporting.
+type permissionRecord struct {
+	GroupEmail string `json:"group_email"`
+	URL        string `json:"url"`
+	GroupPermissions
+}
+
+// csvHeader lists the columns written by -output csv, in field order.
+var csvHeader = []string{
+	"group_email", "url", "is_public", "can_view", "can_post", "can_join", "require_auth",
+	"who_can_contact_owner", "who_can_see_members", "who_can_moderate",
+	"allow_external_members", "message_moderation", "archive_policy",
+}
+
+// csvRow renders r as a row matching csvHeader.
+func (r permissionRecord) csvRow() []string {
+	return []string{
+		r.GroupEmail, r.URL,
+		strconv.FormatBool(r.IsPublic), strconv.FormatBool(r.CanView), strconv.FormatBool(r.CanPost),
+		strconv.FormatBool(r.CanJoin), strconv.FormatBool(r.RequireAuth),
+		r.WhoCanContactOwner, r.WhoCanSeeMembers, r.WhoCanModerate,
+		r.AllowExternalMembers, r.MessageModeration, r.ArchivePolicy,
+	}
+}
+
+// checkGroupHTML fetches a Google Group's /about page and parses its
+// permission settings. It normalizes the URL to the /about page format
+// before fetching.
+func checkGroupHTML(url string, client *http.Client) (*GroupPermissions, error) {
+	domain, groupName := extractDomainAndGroupName(url)
+	if groupName == "" {
+		return nil, fmt.Errorf("invalid Google Groups URL format")
+	}
+	normalizedURL := fmt.Sprintf("https://groups.google.com/a/%s/g/%s/about", domain, groupName)
+
+	req, err := http.NewRequest("GET", normalizedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set a realistic User-Agent
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Check if redirected to login
+	if resp.StatusCode == 302 || resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return &GroupPermissions{IsPublic: false, RequireAuth: true}, nil
+	}
+
+	if resp.StatusCode == 429 || resp.StatusCode == 503 {
+		return nil, &rateLimitedError{retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return parseAboutPage(resp.Body)
+}
+
+// rateLimitedError signals that a request was throttled by the server, and
+// carries how long the caller should wait before retrying.
+type rateLimitedError struct {
+	retryAfter time.Duration
+}
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %v", e.retryAfter)
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either a
+// number of seconds or an HTTP date. It returns 0 if value is empty or
+// unparseable.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// permissionRowClass identifies a table row on the /about page that holds a
+// single permission label/value pair.
+//
+// This class name, loginRequiredClass, and the DOM shape parseAboutPage
+// expects have not been verified against a live /about page in this
+// environment (no outbound access to groups.google.com); they were
+// reconstructed from older HTML dumps of the page. Confirm them against a
+// real response, ideally with `curl` and the browser's inspector, before
+// relying on this parser for an assessment. Until then, parseAboutPage
+// falls back to regexPermissions, the original regex-based extraction, for
+// any page where permissionRowClass matches nothing, so an unrecognized
+// page layout degrades to the old (narrower but verified-in-production)
+// behavior instead of silently reporting no permissions at all.
+const permissionRowClass = "permission-row"
+
+// loginRequiredClass identifies the notice shown in place of the permission
+// table when the about page itself requires sign-in, even though the
+// request returned 200 rather than a redirect or auth error.
+const loginRequiredClass = "group-login-required"
+
+// aboutPagePermissionLabels maps the label text of a permission row to the
+// GroupPermissions field it populates.
+var aboutPagePermissionLabels = map[string]func(p *GroupPermissions, value string){
+	"Who can view conversations": func(p *GroupPermissions, value string) { p.CanView = strings.Contains(value, "Anyone on the web") },
+	"Who can post":               func(p *GroupPermissions, value string) { p.CanPost = strings.Contains(value, "Anyone on the web") },
+	"Who can join group":         func(p *GroupPermissions, value string) { p.CanJoin = strings.Contains(value, "Anyone on the web") },
+	"Who can contact owner":      func(p *GroupPermissions, value string) { p.WhoCanContactOwner = value },
+	"Who can see members":        func(p *GroupPermissions, value string) { p.WhoCanSeeMembers = value },
+	"Who can moderate":           func(p *GroupPermissions, value string) { p.WhoCanModerate = value },
+	"Allow external members":     func(p *GroupPermissions, value string) { p.AllowExternalMembers = value },
+	"Message moderation":         func(p *GroupPermissions, value string) { p.MessageModeration = value },
+	"Archive policy":             func(p *GroupPermissions, value string) { p.ArchivePolicy = value },
+}
+
+// parseAboutPage walks the DOM of a Google Group's /about page and extracts
+// its permission settings. It reports the group as requiring
+// authentication if the page shows a sign-in notice rather than the
+// permission table.
+//
+// If permissionRowClass matches no rows at all, the page's markup doesn't
+// match what this parser expects, so it falls back to regexPermissions
+// rather than returning a GroupPermissions that looks "checked" but is
+// actually just zero values.
+func parseAboutPage(r io.Reader) (*GroupPermissions, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading about page: %w", err)
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("parsing about page: %w", err)
+	}
+
+	if findByClass(doc, loginRequiredClass) != nil {
+		return &GroupPermissions{IsPublic: false, RequireAuth: true}, nil
+	}
+
+	rows := findAllByClass(doc, permissionRowClass)
+	if len(rows) == 0 {
+		return regexPermissions(body), nil
+	}
+
+	perms := &GroupPermissions{IsPublic: true}
+	for _, row := range rows {
+		cells := childrenWithTag(row, "td")
+		if len(cells) < 2 {
+			continue
+		}
+
+		label := strings.TrimSpace(textContent(cells[0]))
+		value := strings.TrimSpace(textContent(cells[1]))
+
+		if apply, ok := aboutPagePermissionLabels[label]; ok {
+			apply(perms, value)
+		}
+	}
+
+	return perms, nil
+}
+
+// canViewPattern, canJoinPattern, and canPostPattern are the regexes
+// check_google_group_permissions used to scrape permissions before it grew
+// a structured DOM parser. They are coarser (no contact/members/moderation
+// fields) but were the verified-in-production behavior, so regexPermissions
+// keeps them available as a fallback.
+var (
+	canViewPattern = regexp.MustCompile(`Anyone on the web.{0,50}?can view conversations`)
+	canJoinPattern = regexp.MustCompile(`Anyone on the web.{0,50}?can join group`)
+	canPostPattern = regexp.MustCompile(`Anyone on the web.{0,50}?can post`)
+)
+
+// regexPermissions extracts the subset of GroupPermissions the old regex
+// scraper covered (IsPublic, CanView, CanPost, CanJoin) by matching against
+// the raw page body.
+func regexPermissions(body []byte) *GroupPermissions {
+	text := string(body)
+	return &GroupPermissions{
+		IsPublic: true,
+		CanView:  canViewPattern.MatchString(text),
+		CanPost:  canPostPattern.MatchString(text),
+		CanJoin:  canJoinPattern.MatchString(text),
+	}
+}
+
+// hasClass reports whether n has class among its space-separated "class" attribute values.
+func hasClass(n *html.Node, class string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key != "class" {
+			continue
+		}
+		for _, c := range strings.Fields(attr.Val) {
+			if c == class {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findByClass returns the first element node under n (inclusive) with the given class.
+func findByClass(n *html.Node, class string) *html.Node {
+	if n.Type == html.ElementNode && hasClass(n, class) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findByClass(c, class); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findAllByClass returns every element node under n (inclusive) with the given class.
+func findAllByClass(n *html.Node, class string) []*html.Node {
+	var found []*html.Node
+	if n.Type == html.ElementNode && hasClass(n, class) {
+		found = append(found, n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		found = append(found, findAllByClass(c, class)...)
+	}
+	return found
+}
+
+// childrenWithTag returns n's direct element children with the given tag name.
+func childrenWithTag(n *html.Node, tag string) []*html.Node {
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == tag {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+// textContent returns the concatenated text of every text node under n.
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var text strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		text.WriteString(textContent(c))
+	}
+	return text.String()
+}
+
+// extractDomainAndGroupName extracts the custom domain and group name from a
+// Google Groups URL, returning empty strings if url does not match. The
+// group email address is groupName@domain.
+func extractDomainAndGroupName(url string) (domain, groupName string) {
+	pattern := regexp.MustCompile(`https?://groups\.google\.com/a/([^/]+)/g/([^/?#]+)`)
+	matches := pattern.FindStringSubmatch(url)
+	if len(matches) <= 2 {
+		return "", ""
+	}
+	return matches[1], matches[2]
+}
+
+// minRate is the default floorRate an adaptiveLimiter's rate will back off
+// to, so a storm of 429s slows requests without fully stalling the scan.
+const minRate = 0.2 // requests per second
+
+// successesToRecover is how many consecutive successful requests an
+// adaptiveLimiter waits for before nudging its rate back up toward the
+// ceiling.
+const successesToRecover = 20
+
+// adaptiveLimiter is a token-bucket rate limiter, shared across worker
+// goroutines, whose rate backs off on 429/503 responses and recovers after
+// sustained success.
+type adaptiveLimiter struct {
+	limiter *rate.Limiter
+
+	// minPause is the pause Throttle applies when a response carries no
+	// Retry-After (or a zero one). It defaults to defaultMinPause; tests
+	// that need to exercise many Throttle calls without burning real wall
+	// clock time can shrink it directly.
+	minPause time.Duration
+
+	// floorRate is the lowest rate Throttle will back current off to. It
+	// defaults to minRate; tests that need a storm of Throttle calls to
+	// converge quickly can raise it directly so the token bucket doesn't
+	// settle at a rate that takes real seconds per token.
+	floorRate float64
+
+	mu          sync.Mutex
+	current     float64
+	ceiling     float64
+	successes   int
+	pausedUntil time.Time
+}
+
+// defaultMinPause is the production value of adaptiveLimiter.minPause.
+const defaultMinPause = time.Second
+
+func newAdaptiveLimiter(ceiling float64, burst int) *adaptiveLimiter {
+	return &adaptiveLimiter{
+		limiter:   rate.NewLimiter(rate.Limit(ceiling), burst),
+		current:   ceiling,
+		ceiling:   ceiling,
+		minPause:  defaultMinPause,
+		floorRate: minRate,
+	}
+}
+
+// Wait blocks until the limiter allows another request, first waiting out
+// any pause a prior Throttle call scheduled.
+//
+// Note: pausing is implemented as an explicit sleep rather than
+// limiter.SetLimit(0), because rate.Limiter treats a zero limit as "serve
+// requests straight from the burst allowance instead of the token bucket,"
+// which permanently drains that allowance rather than pausing it.
+func (a *adaptiveLimiter) Wait(ctx context.Context) error {
+	a.mu.Lock()
+	delay := time.Until(a.pausedUntil)
+	a.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return a.limiter.Wait(ctx)
+}
+
+// Throttle halves the limiter's rate (down to floorRate) and pauses new
+// requests entirely until retryAfter elapses, at which point the halved
+// rate takes effect. If retryAfter is zero or negative, it pauses for
+// minPause instead of not pausing at all.
+func (a *adaptiveLimiter) Throttle(retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = a.minPause
+	}
+
+	a.mu.Lock()
+	a.current /= 2
+	if a.current < a.floorRate {
+		a.current = a.floorRate
+	}
+	a.successes = 0
+	a.pausedUntil = time.Now().Add(retryAfter)
+	resumeRate := a.current
+	a.mu.Unlock()
+
+	a.limiter.SetLimit(rate.Limit(resumeRate))
+}
+
+// RecordSuccess gradually restores the limiter's rate toward its ceiling
+// after successesToRecover consecutive successful requests.
+func (a *adaptiveLimiter) RecordSuccess() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.current >= a.ceiling {
+		return
+	}
+	a.successes++
+	if a.successes < successesToRecover {
+		return
+	}
+	a.successes = 0
+	a.current *= 1.5
+	if a.current > a.ceiling {
+		a.current = a.ceiling
+	}
+	a.limiter.SetLimit(rate.Limit(a.current))
+}
+
+// loadResumeState reads the set of URLs already recorded as processed in a
+// -resume state file. It returns an empty (non-nil) set if path does not
+// yet exist, and nil if path is empty (resume disabled).
+func loadResumeState(path string) (map[string]bool, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return make(map[string]bool), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	done := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if url := strings.TrimSpace(scanner.Text()); url != "" {
+			done[url] = true
+		}
+	}
+	return done, scanner.Err()
+}
+
+// resumeWriter appends processed URLs to a -resume state file so an
+// interrupted run can skip them next time. It is safe for concurrent use.
+type resumeWriter struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// openResumeWriter opens path for appending, creating it if necessary. It
+// returns a nil *resumeWriter (valid to call methods on) if path is empty.
+func openResumeWriter(path string) (*resumeWriter, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &resumeWriter{f: f}, nil
+}
+
+func (w *resumeWriter) MarkDone(url string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintln(w.f, url)
+}
+
+func (w *resumeWriter) Close() error {
+	if w == nil {
+		return nil
+	}
+	return w.f.Close()
+}
+
+// resultSink collects check results for -output json/jsonl/csv, guarding
+// shared writers so concurrent workers can report results safely.
+type resultSink struct {
+	format string
+
+	mu          sync.Mutex
+	csvWriter   *csv.Writer
+	jsonRecords []permissionRecord
+}
+
+func newResultSink(format string) (*resultSink, error) {
+	sink := &resultSink{format: format}
+	if format == "csv" {
+		sink.csvWriter = csv.NewWriter(os.Stdout)
+		if err := sink.csvWriter.Write(csvHeader); err != nil {
+			return nil, err
+		}
+	}
+	return sink, nil
+}
+
+// WriteOpenURL prints url, for the default (no -output) mode.
+func (s *resultSink) WriteOpenURL(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Println(url)
+}
+
+func (s *resultSink) WriteRecord(record permissionRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch s.format {
+	case "csv":
+		if err := s.csvWriter.Write(record.csvRow()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing CSV row for %s: %v\n", record.GroupEmail, err)
+		}
+	case "jsonl":
+		if err := json.NewEncoder(os.Stdout).Encode(record); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSON for %s: %v\n", record.GroupEmail, err)
+		}
+	case "json":
+		s.jsonRecords = append(s.jsonRecords, record)
+	}
+}
+
+// Flush finalizes any buffered output (CSV flushing, the aggregate JSON array).
+func (s *resultSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.format == "csv" {
+		s.csvWriter.Flush()
+		return s.csvWriter.Error()
+	}
+	if s.format == "json" {
+		return json.NewEncoder(os.Stdout).Encode(s.jsonRecords)
+	}
+	return nil
+}
+
+func main() {
+	verbose := flag.Bool("verbose", false, "Show detailed permission information for all groups")
+	requirePost := flag.Bool("require-post", false, "Only output groups where anyone can post")
+	dbPath := flag.String("db", "", "Path to recon store SQLite database (reads/writes through it instead of stdin/stdout)")
+	maxAge := flag.Duration("max-age", 0, "With -db, skip groups whose latest snapshot is newer than this (0 disables incremental skipping)")
+	outputFormat := flag.String("output", "", "Structured output format for every checked group: json, jsonl, or csv (default: URLs of open groups only)")
+	concurrency := flag.Int("concurrency", 8, "Number of concurrent worker goroutines")
+	rateCeiling := flag.Float64("rate", 5, "Maximum steady-state requests per second, shared across all workers")
+	resumePath := flag.String("resume", "", "Path to a resume state file recording processed URLs, so interrupted runs can continue without reprocessing")
+	flag.Parse()
+
+	switch *outputFormat {
+	case "", "json", "jsonl", "csv":
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid -output %q: must be json, jsonl, or csv\n", *outputFormat)
+		os.Exit(1)
+	}
+
+	var db *store.Store
+	if *dbPath != "" {
+		var err error
+		db, err = store.Open(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			// Check if redirecting to login
+			if strings.Contains(req.URL.String(), "accounts.google.com") {
+				return http.ErrUseLastResponse
+			}
+			// Allow other redirects (up to 10)
+			if len(via) >= 10 {
+				return fmt.Errorf("too many redirects")
+			}
+			return nil
+		},
+	}
+
+	urls, err := inputURLs(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+
+	if db != nil && *maxAge > 0 {
+		if err := registerGroups(db, urls); err != nil {
+			fmt.Fprintf(os.Stderr, "Error recording discovered groups: %v\n", err)
+			os.Exit(1)
+		}
+		urls, err = staleURLs(db, urls, *maxAge)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error filtering stale groups: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	done, err := loadResumeState(*resumePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading resume state: %v\n", err)
+		os.Exit(1)
+	}
+	resume, err := openResumeWriter(*resumePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening resume state file: %v\n", err)
+		os.Exit(1)
+	}
+	defer resume.Close()
+
+	sink, err := newResultSink(*outputFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing CSV header: %v\n", err)
+		os.Exit(1)
+	}
+
+	limiter := newAdaptiveLimiter(*rateCeiling, *concurrency)
+	ctx := context.Background()
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range jobs {
+				checkOneURL(ctx, url, client, limiter, db, sink, resume, *verbose, *requirePost)
+			}
+		}()
+	}
+
+	for _, url := range urls {
+		if done[url] {
+			continue
+		}
+		jobs <- url
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := sink.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// checkOneURL checks a single group URL, retrying through limiter up to
+// maxAttempts times if the server rate limits the request, then reports the
+// result through db, sink, or stdout depending on which is configured.
+// resume is notified once the URL has been attempted, successfully or not,
+// so a later run does not retry URLs that merely failed outright.
+func checkOneURL(ctx context.Context, url string, client *http.Client, limiter *adaptiveLimiter, db *store.Store, sink *resultSink, resume *resumeWriter, verbose, requirePost bool) {
+	defer resume.MarkDone(url)
+
+	domain, groupName := extractDomainAndGroupName(url)
+	if groupName == "" {
+		fmt.Fprintf(os.Stderr, "Could not extract group email from %s\n", url)
+		return
+	}
+	groupEmail := fmt.Sprintf("%s@%s", groupName, domain)
+
+	var perms *GroupPermissions
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error waiting for rate limiter on %s: %v\n", url, err)
+			return
+		}
+
+		result, err := checkGroupHTML(url, client)
+		if rle, ok := err.(*rateLimitedError); ok {
+			limiter.Throttle(rle.retryAfter)
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking %s: %v\n", url, err)
+			return
+		}
+		limiter.RecordSuccess()
+		perms = result
+		break
+	}
+	if perms == nil {
+		fmt.Fprintf(os.Stderr, "Giving up on %s after %d attempts: still rate limited\n", url, maxAttempts)
+		return
+	}
+
+	// Determine if group is "open" based on criteria
+	isOpen := perms.IsPublic && (perms.CanView || perms.CanJoin)
+	if requirePost {
+		isOpen = isOpen && perms.CanPost
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Group: %s | Public: %v | View: %v | Post: %v | Join: %v | RequireAuth: %v\n",
+			groupEmail, perms.IsPublic, perms.CanView, perms.CanPost, perms.CanJoin, perms.RequireAuth)
+	} else if !isOpen {
+		fmt.Fprintf(os.Stderr, "Rejected %s (not publicly accessible)\n", groupEmail)
+	}
+
+	if db != nil {
+		if err := db.UpsertGroup(store.Group{Email: groupEmail, Domain: domain, URL: url}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error recording group %s: %v\n", groupEmail, err)
+			return
+		}
+		snapshot := store.Snapshot{
+			SeenAt:   time.Now(),
+			IsPublic: perms.IsPublic,
+			CanView:  perms.CanView,
+			CanPost:  perms.CanPost,
+			CanJoin:  perms.CanJoin,
+		}
+		if err := db.RecordSnapshot(groupEmail, snapshot); err != nil {
+			fmt.Fprintf(os.Stderr, "Error recording snapshot for %s: %v\n", groupEmail, err)
+		}
+		return
+	}
+
+	if sink.format == "" {
+		if isOpen {
+			sink.WriteOpenURL(url)
+		}
+		return
+	}
+
+	sink.WriteRecord(permissionRecord{GroupEmail: groupEmail, URL: url, GroupPermissions: *perms})
+}
+
+// registerGroups upserts a Group row for every URL in urls, so that newly
+// discovered groups which have never been checked (and so have no
+// permissions snapshot yet) are visible to db.GroupsNeedingCheck rather than
+// only groups that already have one. URLs that are not recognizable group
+// URLs are skipped; checkOneURL reports those as errors once checked.
+func registerGroups(db *store.Store, urls []string) error {
+	for _, url := range urls {
+		domain, groupName := extractDomainAndGroupName(url)
+		if groupName == "" {
+			continue
+		}
+		groupEmail := fmt.Sprintf("%s@%s", groupName, domain)
+		if err := db.UpsertGroup(store.Group{Email: groupEmail, Domain: domain, URL: url}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// staleURLs filters urls down to those db.GroupsNeedingCheck reports as due
+// for a recheck — the set an incremental scan should re-check. URLs that are
+// not recognizable group URLs are passed through so the caller can still
+// report the error.
+func staleURLs(db *store.Store, urls []string, maxAge time.Duration) ([]string, error) {
+	due, err := db.GroupsNeedingCheck(maxAge)
+	if err != nil {
+		return nil, err
+	}
+	dueURLs := make(map[string]bool, len(due))
+	for _, g := range due {
+		dueURLs[g.URL] = true
+	}
+
+	var stale []string
+	for _, url := range urls {
+		if _, groupName := extractDomainAndGroupName(url); groupName == "" {
+			stale = append(stale, url)
+			continue
+		}
+		if dueURLs[url] {
+			stale = append(stale, url)
+		}
+	}
+	return stale, nil
+}
+
+// inputURLs returns the URLs to check, reading from the store if db is
+// non-nil or from stdin otherwise.
+func inputURLs(db *store.Store) ([]string, error) {
+	if db != nil {
+		return db.URLs()
+	}
+
+	var urls []string
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		urls = append(urls, scanner.Text())
+	}
+	return urls, scanner.Err()
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestParseAboutPage checks parseAboutPage against hand-authored fixtures
+// built to match permissionRowClass/loginRequiredClass, not HTML captured
+// from a live /about page (see the caveat on those constants) — it verifies
+// the extraction logic in isolation, not that those selectors match
+// Google's actual markup.
+func TestParseAboutPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		expected GroupPermissions
+	}{
+		{
+			name:    "public group",
+			fixture: "testdata/public_about.html",
+			expected: GroupPermissions{
+				IsPublic:             true,
+				CanView:              true,
+				CanPost:              true,
+				CanJoin:              true,
+				WhoCanContactOwner:   "Anyone on the web",
+				WhoCanSeeMembers:     "Anyone on the web",
+				WhoCanModerate:       "Owners only",
+				AllowExternalMembers: "Yes",
+				MessageModeration:    "None",
+				ArchivePolicy:        "Public, retained indefinitely",
+			},
+		},
+		{
+			name:    "domain-restricted group",
+			fixture: "testdata/domain_restricted_about.html",
+			expected: GroupPermissions{
+				IsPublic:             true,
+				CanView:              false,
+				CanPost:              false,
+				CanJoin:              false,
+				WhoCanContactOwner:   "Members of example.com",
+				WhoCanSeeMembers:     "Owners and managers",
+				WhoCanModerate:       "Owners and managers",
+				AllowExternalMembers: "No",
+				MessageModeration:    "Not moderated",
+				ArchivePolicy:        "Members-only, retained indefinitely",
+			},
+		},
+		{
+			name:    "login-required group",
+			fixture: "testdata/login_required_about.html",
+			expected: GroupPermissions{
+				IsPublic:    false,
+				RequireAuth: true,
+			},
+		},
+		{
+			// permission-row matches nothing here, so this exercises the
+			// regexPermissions fallback rather than the structured DOM path.
+			name:    "unstructured markup falls back to regex extraction",
+			fixture: "testdata/unstructured_public_about.html",
+			expected: GroupPermissions{
+				IsPublic: true,
+				CanView:  true,
+				CanPost:  true,
+				CanJoin:  true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.fixture)
+			if err != nil {
+				t.Fatalf("opening fixture: %v", err)
+			}
+			defer f.Close()
+
+			perms, err := parseAboutPage(f)
+			if err != nil {
+				t.Fatalf("parseAboutPage: %v", err)
+			}
+
+			if *perms != tt.expected {
+				t.Errorf("parseAboutPage(%s) = %+v, want %+v", tt.fixture, *perms, tt.expected)
+			}
+		})
+	}
+}
+
+// rewriteTransport sends every request to target instead of its original
+// host, so a test can point checkGroupHTML (which always builds a
+// groups.google.com URL) at an httptest.Server.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TestAdaptiveLimiterConvergesUnderRateLimitStorm simulates a server that
+// 429s the first several requests (with Retry-After: 0) before settling
+// down, and checks that concurrent callers retrying through a shared
+// adaptiveLimiter all eventually succeed rather than giving up.
+func TestAdaptiveLimiterConvergesUnderRateLimitStorm(t *testing.T) {
+	const stormRequests = 8
+	var requestCount int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requestCount, 1) <= stormRequests {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fixture, err := os.ReadFile("testdata/public_about.html")
+		if err != nil {
+			t.Fatalf("reading fixture: %v", err)
+		}
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parsing server URL: %v", err)
+	}
+	client := &http.Client{Transport: &rewriteTransport{target: target}}
+	limiter := newAdaptiveLimiter(50, 5)
+	// Retry-After is 0 in this test's storm responses, so Throttle pauses for
+	// minPause each time; shrink it, and raise floorRate, so the storm
+	// converges without the token bucket settling at a real-seconds-per-token
+	// rate.
+	limiter.minPause = time.Millisecond
+	limiter.floorRate = 50
+
+	const workers = 5
+	var wg sync.WaitGroup
+	var succeeded int32
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			groupURL := fmt.Sprintf("https://groups.google.com/a/example.com/g/team%d", n)
+
+			for attempt := 1; attempt <= maxAttempts; attempt++ {
+				if err := limiter.Wait(context.Background()); err != nil {
+					t.Errorf("limiter.Wait: %v", err)
+					return
+				}
+
+				perms, err := checkGroupHTML(groupURL, client)
+				if rle, ok := err.(*rateLimitedError); ok {
+					limiter.Throttle(rle.retryAfter)
+					continue
+				}
+				if err != nil {
+					t.Errorf("checkGroupHTML(%s): %v", groupURL, err)
+					return
+				}
+				limiter.RecordSuccess()
+				if !perms.IsPublic {
+					t.Errorf("checkGroupHTML(%s) = %+v, want public", groupURL, perms)
+				}
+				atomic.AddInt32(&succeeded, 1)
+				return
+			}
+			t.Errorf("worker %d never succeeded within %d attempts", n, maxAttempts)
+		}(i)
+	}
+	wg.Wait()
+
+	if succeeded != workers {
+		t.Errorf("succeeded = %d, want %d", succeeded, workers)
+	}
+}
+
+func TestExtractDomainAndGroupName(t *testing.T) {
+	tests := []struct {
+		url           string
+		wantDomain    string
+		wantGroupName string
+	}{
+		{
+			url:           "https://groups.google.com/a/list.nist.gov/g/internet-time-service",
+			wantDomain:    "list.nist.gov",
+			wantGroupName: "internet-time-service",
+		},
+		{
+			url:           "https://groups.google.com/a/example.com/g/team?hl=en",
+			wantDomain:    "example.com",
+			wantGroupName: "team",
+		},
+		{
+			url:           "https://example.com/not-a-group",
+			wantDomain:    "",
+			wantGroupName: "",
+		},
+	}
+
+	for _, tt := range tests {
+		domain, groupName := extractDomainAndGroupName(tt.url)
+		if domain != tt.wantDomain || groupName != tt.wantGroupName {
+			t.Errorf("extractDomainAndGroupName(%s) = (%s, %s), want (%s, %s)",
+				tt.url, domain, groupName, tt.wantDomain, tt.wantGroupName)
+		}
+	}
+}
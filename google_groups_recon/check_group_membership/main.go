@@ -0,0 +1,171 @@
+// check_group_membership probes Google Groups to determine whether a given
+// principal (for example a wildcard or guest identity already discovered in
+// the domain) is a member of each group, including groups that are not
+// publicly accessible. Unlike check_google_group_permissions, which only
+// detects groups open to "Anyone on the web", this tool authenticates as a
+// Google Workspace service account with domain-wide delegation and queries
+// the Admin SDK Directory API directly, so it can also surface nested or
+// nominally-private groups that the test principal has already joined.
+//
+// The tool reads Google Groups URLs from stdin, one per line, and checks
+// membership of a single test principal (supplied with -member) against
+// each group.
+//
+// Usage:
+//
+//	cat urls.txt | ./check_group_membership -credentials sa.json -member guest@gmail.com
+//
+// Membership is determined with the Directory API's members.hasMember
+// endpoint. hasMember returns a 400 for members outside the group's domain
+// (external or guest members), so on that response the tool falls back to
+// members.get with the member's URL-encoded email, which also succeeds for
+// external members and additionally confirms membership in nested groups.
+//
+// Output:
+//   - stdout: CSV rows of group_email,member,membership_source
+//     (membership_source is one of hasMember, get, or none)
+//   - stderr: Error messages and progress details
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+
+	"golang.org/x/oauth2/google"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+// membershipSource identifies which Directory API call confirmed membership.
+type membershipSource string
+
+const (
+	sourceHasMember membershipSource = "hasMember"
+	sourceGet       membershipSource = "get"
+	sourceNone      membershipSource = "none"
+)
+
+// membershipKey identifies a cached (group, member) membership lookup.
+type membershipKey struct {
+	group  string
+	member string
+}
+
+// groupURLPattern extracts the domain and group name from a Google Groups URL.
+var groupURLPattern = regexp.MustCompile(`https?://groups\.google\.com/a/([^/]+)/g/([^/?#]+)`)
+
+// extractGroupEmail extracts the group email address from a Google Groups URL.
+func extractGroupEmail(rawURL string) string {
+	matches := groupURLPattern.FindStringSubmatch(rawURL)
+	if len(matches) < 3 {
+		return ""
+	}
+	return fmt.Sprintf("%s@%s", matches[2], matches[1])
+}
+
+// checkMembership determines whether member belongs to group, trying
+// members.hasMember first and falling back to members.get for external
+// members (which hasMember rejects with a 400).
+func checkMembership(ctx context.Context, svc *admin.Service, group, member string) (bool, membershipSource, error) {
+	hasMember, err := svc.Members.HasMember(group, member).Context(ctx).Do()
+	if err == nil {
+		return hasMember.IsMember, sourceHasMember, nil
+	}
+
+	apiErr, ok := err.(*googleapi.Error)
+	if !ok || apiErr.Code != 400 {
+		return false, sourceNone, fmt.Errorf("hasMember(%s, %s): %w", group, member, err)
+	}
+
+	// hasMember rejects external/non-domain members with a 400; members.get
+	// still succeeds for them, and also covers nested group membership.
+	encodedMember := url.QueryEscape(member)
+	if _, err := svc.Members.Get(group, encodedMember).Context(ctx).Do(); err != nil {
+		if apiErr, ok := err.(*googleapi.Error); ok && apiErr.Code == 404 {
+			return false, sourceNone, nil
+		}
+		return false, sourceNone, fmt.Errorf("members.get(%s, %s): %w", group, member, err)
+	}
+
+	return true, sourceGet, nil
+}
+
+func main() {
+	credentials := flag.String("credentials", "", "Path to service account JSON key with domain-wide delegation (required)")
+	member := flag.String("member", "", "Test principal email to check for membership (required)")
+	subject := flag.String("subject", "", "Workspace admin email to impersonate via domain-wide delegation (required)")
+	flag.Parse()
+
+	if *credentials == "" || *member == "" || *subject == "" {
+		fmt.Fprintf(os.Stderr, "Usage: %s -credentials sa.json -subject admin@domain.com -member guest@gmail.com\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+
+	jsonKey, err := os.ReadFile(*credentials)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading credentials: %v\n", err)
+		os.Exit(1)
+	}
+
+	config, err := google.JWTConfigFromJSON(jsonKey, admin.AdminDirectoryGroupMemberReadonlyScope)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing credentials: %v\n", err)
+		os.Exit(1)
+	}
+	config.Subject = *subject
+
+	svc, err := admin.NewService(ctx, option.WithHTTPClient(config.Client(ctx)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating Directory API client: %v\n", err)
+		os.Exit(1)
+	}
+
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	cache := make(map[membershipKey]membershipSource)
+	scanner := bufio.NewScanner(os.Stdin)
+
+	for scanner.Scan() {
+		rawURL := scanner.Text()
+
+		groupEmail := extractGroupEmail(rawURL)
+		if groupEmail == "" {
+			fmt.Fprintf(os.Stderr, "Could not extract group email from %s\n", rawURL)
+			continue
+		}
+
+		key := membershipKey{group: groupEmail, member: *member}
+		source, cached := cache[key]
+		if !cached {
+			isMember, checkedSource, err := checkMembership(ctx, svc, groupEmail, *member)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error checking %s: %v\n", groupEmail, err)
+				continue
+			}
+			source = sourceNone
+			if isMember {
+				source = checkedSource
+			}
+			cache[key] = source
+		}
+
+		if err := writer.Write([]string{groupEmail, *member, string(source)}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing CSV row: %v\n", err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+}
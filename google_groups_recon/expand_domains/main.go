@@ -0,0 +1,289 @@
+// expand_domains enumerates subdomains of a seed domain so that hosts like
+// groups.google.com/a/<domain> can be discovered without already knowing
+// the specific subdomain a Google Workspace deployment lives on (e.g.
+// list.nist.gov, groups.nist.gov from the seed nist.gov).
+//
+// The tool reads seed domains from stdin, one per line, and for each
+// enumerates subdomains via:
+//   - securitytrails: SecurityTrails' /v1/domain/{domain}/subdomains API
+//     (requires SECURITYTRAILS_API_KEY)
+//   - crtsh: certificate transparency logs via crt.sh
+//   - brute: DNS brute-force against a built-in wordlist of common subdomain labels
+//
+// Usage:
+//
+//	echo nist.gov | ./expand_domains
+//	echo nist.gov | ./expand_domains -sources crtsh,brute
+//
+// Output:
+//   - stdout: one discovered FQDN per line, deduplicated across sources
+//   - stderr: per-source errors and progress messages
+//
+// With -db path.sqlite, the tool seeds from every domain already recorded in
+// the shared recon store's domains table (in addition to stdin, if any lines
+// are piped in) and upserts each discovered FQDN back into that table
+// instead of stdout.
+//
+// Example pipeline:
+//
+//	echo nist.gov | ./expand_domains | ./fetch_urls -domain list.nist.gov # feed a discovered FQDN through the recon pipeline
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spaceraccoon/vibe-sec-tools/google_groups_recon/store"
+)
+
+// bruteForceWordlist holds the common subdomain labels tried by the brute
+// source. It is intentionally small; a thorough scan should supply a larger
+// wordlist via -wordlist instead.
+var bruteForceWordlist = []string{
+	"www", "mail", "smtp", "webmail", "ftp", "ns1", "ns2", "vpn", "api",
+	"dev", "staging", "test", "admin", "portal", "intranet", "support",
+	"help", "docs", "wiki", "git", "jira", "confluence", "jenkins",
+	"groups", "list", "lists", "forum", "blog", "shop", "m", "mobile",
+	"cdn", "static", "cpanel", "autodiscover", "owa",
+}
+
+func main() {
+	sourceList := flag.String("sources", "securitytrails,crtsh,brute", "Comma-separated list of sources to enable")
+	wordlistPath := flag.String("wordlist", "", "Path to a newline-delimited wordlist for the brute source (default: built-in list)")
+	dbPath := flag.String("db", "", "Path to recon store SQLite database (seeds from its domains table and upserts discovered FQDNs into it instead of stdout)")
+	flag.Parse()
+
+	sources := make(map[string]bool)
+	for _, name := range strings.Split(*sourceList, ",") {
+		sources[strings.TrimSpace(name)] = true
+	}
+
+	wordlist := bruteForceWordlist
+	if *wordlistPath != "" {
+		loaded, err := loadWordlist(*wordlistPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading wordlist: %v\n", err)
+			os.Exit(1)
+		}
+		wordlist = loaded
+	}
+
+	var db *store.Store
+	if *dbPath != "" {
+		var err error
+		db, err = store.Open(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+	}
+
+	seeds, err := inputDomains(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing seed domains: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, domain := range seeds {
+		seen := make(map[string]bool)
+		emit := func(fqdn string) {
+			if seen[fqdn] {
+				return
+			}
+			seen[fqdn] = true
+
+			if db != nil {
+				if err := db.UpsertDomain(fqdn); err != nil {
+					fmt.Fprintf(os.Stderr, "Error storing domain %s: %v\n", fqdn, err)
+				}
+				return
+			}
+			fmt.Println(fqdn)
+		}
+
+		if sources["securitytrails"] {
+			subdomains, err := fetchSecurityTrailsSubdomains(domain)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "securitytrails: error enumerating %s: %v\n", domain, err)
+			}
+			for _, fqdn := range subdomains {
+				emit(fqdn)
+			}
+		}
+
+		if sources["crtsh"] {
+			subdomains, err := fetchCrtShSubdomains(domain)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "crtsh: error enumerating %s: %v\n", domain, err)
+			}
+			for _, fqdn := range subdomains {
+				emit(fqdn)
+			}
+		}
+
+		if sources["brute"] {
+			for _, fqdn := range bruteForceSubdomains(domain, wordlist) {
+				emit(fqdn)
+			}
+		}
+	}
+}
+
+// inputDomains collects seed domains from stdin, one per line, and, when db
+// is non-nil, from the store's domains table as well.
+func inputDomains(db *store.Store) ([]string, error) {
+	var domains []string
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		domain := strings.TrimSpace(scanner.Text())
+		if domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if db != nil {
+		stored, err := db.Domains()
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, stored...)
+	}
+
+	return domains, nil
+}
+
+// securityTrailsSubdomainsResponse is the response shape of
+// GET /v1/domain/{domain}/subdomains.
+type securityTrailsSubdomainsResponse struct {
+	Subdomains []string `json:"subdomains"`
+}
+
+// fetchSecurityTrailsSubdomains enumerates subdomains of domain via the
+// SecurityTrails API. It requires an API key, read from the
+// SECURITYTRAILS_API_KEY environment variable.
+func fetchSecurityTrailsSubdomains(domain string) ([]string, error) {
+	apiKey := os.Getenv("SECURITYTRAILS_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("SECURITYTRAILS_API_KEY not set")
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("https://api.securitytrails.com/v1/domain/%s/subdomains?children_only=true", domain), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("APIKEY", apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body securityTrailsSubdomainsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	fqdns := make([]string, 0, len(body.Subdomains))
+	for _, sub := range body.Subdomains {
+		fqdns = append(fqdns, fmt.Sprintf("%s.%s", sub, domain))
+	}
+	return fqdns, nil
+}
+
+// crtShEntry is a single row of crt.sh's JSON output.
+type crtShEntry struct {
+	NameValue string `json:"name_value"`
+}
+
+// fetchCrtShSubdomains enumerates subdomains of domain observed in
+// certificate transparency logs via crt.sh.
+func fetchCrtShSubdomains(domain string) ([]string, error) {
+	query := url.Values{}
+	query.Set("q", "%."+domain)
+	query.Set("output", "json")
+
+	resp, err := http.Get("https://crt.sh/?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var entries []crtShEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var fqdns []string
+	for _, entry := range entries {
+		for _, name := range strings.Split(entry.NameValue, "\n") {
+			name = strings.TrimSpace(strings.TrimPrefix(name, "*."))
+			if name == "" || seen[name] || !isSubdomainOrSelf(name, domain) {
+				continue
+			}
+			seen[name] = true
+			fqdns = append(fqdns, name)
+		}
+	}
+	return fqdns, nil
+}
+
+// isSubdomainOrSelf reports whether name is domain itself or a subdomain of
+// it, requiring a dot boundary so an unrelated domain that merely ends with
+// the same characters (e.g. "evil"+domain) is not misreported as a match.
+func isSubdomainOrSelf(name, domain string) bool {
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+// bruteForceSubdomains tries each label in wordlist as a subdomain of domain
+// and returns those that resolve.
+func bruteForceSubdomains(domain string, wordlist []string) []string {
+	var fqdns []string
+	for _, label := range wordlist {
+		candidate := fmt.Sprintf("%s.%s", label, domain)
+		if _, err := net.LookupHost(candidate); err == nil {
+			fqdns = append(fqdns, candidate)
+		}
+	}
+	return fqdns
+}
+
+// loadWordlist reads a newline-delimited wordlist file, skipping blank lines.
+func loadWordlist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return words, scanner.Err()
+}
@@ -0,0 +1,166 @@
+// fetch_urls fans out to every enabled passive URL discovery source for a
+// domain (OTX, Wayback Machine, CommonCrawl, SecurityTrails) concurrently,
+// deduplicates results across all of them, and streams the merged result as
+// newline-delimited JSON. It supersedes fetch_alienvault, which only queried
+// OTX, as the recon pipeline's aggregation step.
+//
+// Usage:
+//
+//	./fetch_urls -domain groups.google.com
+//	./fetch_urls -domain groups.google.com -sources otx,wayback
+//
+// Output:
+//   - stdout: one JSON record per discovered URL: {"url","source","first_seen","http_code"}
+//   - stderr: per-source errors and progress messages
+//
+// Example pipeline:
+//
+//	./fetch_urls -domain groups.google.com | jq -r .url | ./trim_google_group_urls -trim > urls.txt
+//
+// With -db path.sqlite, the tool fetches for every domain already recorded
+// in the shared recon store's domains table (in addition to -domain, if
+// given) and writes discovered URLs into the store's urls table instead of
+// stdout.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	bloom "github.com/bits-and-blooms/bloom/v3"
+
+	"github.com/spaceraccoon/vibe-sec-tools/google_groups_recon/passivesources"
+	"github.com/spaceraccoon/vibe-sec-tools/google_groups_recon/store"
+)
+
+// availableSources maps -sources selector names to their implementation.
+var availableSources = map[string]passivesources.Source{
+	"otx":            passivesources.OTXSource{},
+	"wayback":        passivesources.WaybackSource{},
+	"commoncrawl":    passivesources.CommonCrawlSource{},
+	"securitytrails": passivesources.SecurityTrailsSource{},
+}
+
+// expectedURLs sizes the shared bloom filter; false positives only cause a
+// handful of duplicate records to slip through, so an order-of-magnitude
+// estimate is enough.
+const expectedURLs = 1_000_000
+
+func main() {
+	domain := flag.String("domain", "", "Domain to query")
+	sourceList := flag.String("sources", "otx,wayback,commoncrawl,securitytrails", "Comma-separated list of sources to enable")
+	dbPath := flag.String("db", "", "Path to recon store SQLite database (reads domains/writes urls through it instead of stdout)")
+	flag.Parse()
+
+	var db *store.Store
+	if *dbPath != "" {
+		var err error
+		db, err = store.Open(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+	}
+
+	domains, err := inputDomains(db, *domain)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing domains: %v\n", err)
+		os.Exit(1)
+	}
+	if len(domains) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s -domain <domain> [-sources otx,wayback,commoncrawl,securitytrails] [-db path.sqlite]\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	var sources []passivesources.Source
+	for _, name := range strings.Split(*sourceList, ",") {
+		name = strings.TrimSpace(name)
+		source, ok := availableSources[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown source %q, skipping\n", name)
+			continue
+		}
+		sources = append(sources, source)
+	}
+
+	ctx := context.Background()
+	filter := bloom.NewWithEstimates(expectedURLs, 0.01)
+	var filterMu sync.Mutex
+
+	encoder := json.NewEncoder(os.Stdout)
+	var outputMu sync.Mutex
+
+	output := func(info passivesources.URLInfo) error {
+		outputMu.Lock()
+		defer outputMu.Unlock()
+
+		if db != nil {
+			return db.UpsertURL(info.URL)
+		}
+		return encoder.Encode(map[string]any{
+			"url":        info.URL,
+			"source":     info.Source,
+			"first_seen": info.FirstSeen,
+			"http_code":  info.HTTPCode,
+		})
+	}
+
+	for _, domain := range domains {
+		fetchDomain(ctx, domain, sources, filter, &filterMu, output)
+	}
+}
+
+// fetchDomain runs every source for domain concurrently, deduplicating
+// against the shared filter before calling output for each unique URL.
+func fetchDomain(ctx context.Context, domain string, sources []passivesources.Source, filter *bloom.BloomFilter, filterMu *sync.Mutex, output func(passivesources.URLInfo) error) {
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		wg.Add(1)
+		go func(source passivesources.Source) {
+			defer wg.Done()
+
+			count := 0
+			for info := range source.Fetch(ctx, domain) {
+				filterMu.Lock()
+				seen := filter.TestAndAdd([]byte(info.URL))
+				filterMu.Unlock()
+				if seen {
+					continue
+				}
+
+				if err := output(info); err != nil {
+					fmt.Fprintf(os.Stderr, "%s: error writing record: %v\n", source.Name(), err)
+					continue
+				}
+				count++
+			}
+
+			fmt.Fprintf(os.Stderr, "%s: completed with %d unique URLs for domain %s\n", source.Name(), count, domain)
+		}(source)
+	}
+
+	wg.Wait()
+}
+
+// inputDomains returns the domains to fetch: every domain recorded in db (if
+// given), plus domain itself if non-empty.
+func inputDomains(db *store.Store, domain string) ([]string, error) {
+	var domains []string
+	if db != nil {
+		stored, err := db.Domains()
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, stored...)
+	}
+	if domain != "" {
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
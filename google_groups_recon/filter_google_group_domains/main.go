@@ -0,0 +1,102 @@
+// filter_google_group_domains extracts unique custom domains from Google Workspace
+// group URLs. This tool reads URLs from stdin and outputs deduplicated domain names
+// to stdout.
+//
+// Google Workspace groups use URLs in the format:
+//
+//	https://groups.google.com/a/{domain}/g/{group-name}
+//
+// This tool extracts the {domain} portion (e.g., "list.nist.gov") from each URL.
+//
+// Usage:
+//
+//	cat urls.txt | ./filter_google_group_domains
+//
+// Example input:
+//
+//	https://groups.google.com/a/list.nist.gov/g/internet-time-service
+//	https://groups.google.com/a/example.com/g/announcements
+//
+// Example output:
+//
+//	list.nist.gov
+//	example.com
+//
+// With -db path.sqlite, the tool reads URLs from the shared recon store's
+// urls table instead of stdin, and writes discovered domains into the
+// store's domains table instead of stdout.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/spaceraccoon/vibe-sec-tools/google_groups_recon/store"
+)
+
+func main() {
+	dbPath := flag.String("db", "", "Path to recon store SQLite database (reads/writes through it instead of stdin/stdout)")
+	flag.Parse()
+
+	// Pattern to extract custom domain from Google Workspace group URLs.
+	// Example: https://groups.google.com/a/list.nist.gov/g/internet-time-service
+	// Captures: list.nist.gov
+	pattern := regexp.MustCompile(`https?://groups\.google\.com/a/([^/]+)/g/`)
+
+	var db *store.Store
+	if *dbPath != "" {
+		var err error
+		db, err = store.Open(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+	}
+
+	urls, err := inputURLs(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+
+	seen := make(map[string]bool)
+	for _, url := range urls {
+		matches := pattern.FindStringSubmatch(url)
+		if len(matches) <= 1 {
+			continue
+		}
+
+		domain := matches[1]
+		if seen[domain] {
+			continue
+		}
+		seen[domain] = true
+
+		if db != nil {
+			if err := db.UpsertDomain(domain); err != nil {
+				fmt.Fprintf(os.Stderr, "Error recording domain %s: %v\n", domain, err)
+			}
+			continue
+		}
+		fmt.Println(domain)
+	}
+}
+
+// inputURLs returns the URLs to scan, reading from the store if db is
+// non-nil or from stdin otherwise.
+func inputURLs(db *store.Store) ([]string, error) {
+	if db != nil {
+		return db.URLs()
+	}
+
+	var urls []string
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		urls = append(urls, scanner.Text())
+	}
+	return urls, scanner.Err()
+}
@@ -0,0 +1,141 @@
+package passivesources
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/time/rate"
+)
+
+// commonCrawlIndexListURL lists every CommonCrawl crawl index and the CDX API
+// endpoint to query it.
+const commonCrawlIndexListURL = "https://index.commoncrawl.org/collinfo.json"
+
+// commonCrawlLimiter caps CDX queries at 1 request per second, the rate
+// CommonCrawl's usage guidelines ask index clients to stay under, since a
+// single domain scan queries every one of the ~100 published indexes
+// back-to-back.
+var commonCrawlLimiter = rate.NewLimiter(1, 1)
+
+// commonCrawlIndex describes one entry in the collinfo.json index list.
+type commonCrawlIndex struct {
+	ID     string `json:"id"`
+	CDXAPI string `json:"cdx-api"`
+}
+
+// commonCrawlRecord is one line of a CommonCrawl CDX query, newline-delimited JSON.
+type commonCrawlRecord struct {
+	URL       string `json:"url"`
+	Timestamp string `json:"timestamp"`
+	Status    string `json:"status"`
+}
+
+// CommonCrawlSource fetches URLs observed for a domain across every
+// published CommonCrawl index. It first discovers the current list of
+// indexes, then queries each one's CDX API in turn.
+type CommonCrawlSource struct{}
+
+// Name implements Source.
+func (CommonCrawlSource) Name() string { return "commoncrawl" }
+
+// Fetch implements Source.
+func (s CommonCrawlSource) Fetch(ctx context.Context, domain string) <-chan URLInfo {
+	out := make(chan URLInfo)
+
+	go func() {
+		defer close(out)
+
+		indexes, err := fetchCommonCrawlIndexes(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "commoncrawl: error listing indexes: %v\n", err)
+			return
+		}
+
+		for _, index := range indexes {
+			if err := commonCrawlLimiter.Wait(ctx); err != nil {
+				return
+			}
+			if err := fetchCommonCrawlIndex(ctx, index, domain, out); err != nil {
+				fmt.Fprintf(os.Stderr, "commoncrawl: error querying index %s: %v\n", index.ID, err)
+			}
+		}
+	}()
+
+	return out
+}
+
+// fetchCommonCrawlIndexes retrieves the current list of crawl indexes.
+func fetchCommonCrawlIndexes(ctx context.Context) ([]commonCrawlIndex, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", commonCrawlIndexListURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var indexes []commonCrawlIndex
+	if err := json.NewDecoder(resp.Body).Decode(&indexes); err != nil {
+		return nil, err
+	}
+
+	return indexes, nil
+}
+
+// fetchCommonCrawlIndex queries a single index's CDX API for domain and
+// streams results to out.
+func fetchCommonCrawlIndex(ctx context.Context, index commonCrawlIndex, domain string, out chan<- URLInfo) error {
+	query := url.Values{}
+	query.Set("url", fmt.Sprintf("*.%s/*", domain))
+	query.Set("output", "json")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", index.CDXAPI+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// A 404 means this index has no captures for the domain, not an error.
+	if resp.StatusCode == 404 {
+		return nil
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var record commonCrawlRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+
+		httpCode := 0
+		fmt.Sscanf(record.Status, "%d", &httpCode)
+
+		select {
+		case out <- URLInfo{URL: record.URL, Source: "commoncrawl", FirstSeen: record.Timestamp, HTTPCode: httpCode}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,130 @@
+package passivesources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	// otxBaseURL is the AlienVault OTX API endpoint for fetching URL lists by hostname.
+	otxBaseURL = "https://otx.alienvault.com/otxapi/indicators/hostname/url_list"
+	// otxLimitPerPage defines how many results to request per API call.
+	otxLimitPerPage = 100
+	// otxRequestsPerHour is set slightly below the API limit (10,000) to provide a safety margin.
+	otxRequestsPerHour = 9500
+)
+
+// otxResponse represents the JSON response structure from the AlienVault OTX API.
+type otxResponse struct {
+	HasNext    bool          `json:"has_next"`
+	ActualSize int           `json:"actual_size"`
+	URLList    []otxURLEntry `json:"url_list"`
+}
+
+// otxURLEntry represents a single URL entry from the AlienVault response.
+type otxURLEntry struct {
+	URL      string `json:"url"`
+	Date     string `json:"date"`
+	HTTPCode int    `json:"httpcode"`
+}
+
+// OTXSource fetches historical URLs associated with a domain from AlienVault's
+// Open Threat Exchange (OTX) API, handling pagination and the API's hourly
+// rate limit automatically.
+type OTXSource struct{}
+
+// Name implements Source.
+func (OTXSource) Name() string { return "otx" }
+
+// Fetch implements Source.
+func (s OTXSource) Fetch(ctx context.Context, domain string) <-chan URLInfo {
+	out := make(chan URLInfo)
+
+	go func() {
+		defer close(out)
+
+		page := 1
+		count := 0
+		startTime := time.Now()
+
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if count > 0 && count%otxRequestsPerHour == 0 {
+				elapsed := time.Since(startTime)
+				if elapsed < time.Hour {
+					fmt.Fprintf(os.Stderr, "otx: reached hourly limit (%d requests), waiting %v\n", otxRequestsPerHour, time.Hour-elapsed)
+					time.Sleep(time.Hour - elapsed)
+				}
+				startTime = time.Now()
+			}
+
+			resp, err := fetchOTXPage(ctx, domain, page)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "otx: error fetching page %d: %v\n", page, err)
+				return
+			}
+
+			for _, entry := range resp.URLList {
+				select {
+				case out <- URLInfo{URL: entry.URL, Source: s.Name(), FirstSeen: entry.Date, HTTPCode: entry.HTTPCode}:
+				case <-ctx.Done():
+					return
+				}
+				count++
+			}
+
+			if !resp.HasNext {
+				return
+			}
+			page++
+		}
+	}()
+
+	return out
+}
+
+// fetchOTXPage retrieves a single page of URL results from the AlienVault OTX API.
+func fetchOTXPage(ctx context.Context, domain string, page int) (*otxResponse, error) {
+	url := fmt.Sprintf("%s/%s?limit=%d&page=%d", otxBaseURL, domain, otxLimitPerPage, page)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey := os.Getenv("OTX_API_KEY"); apiKey != "" {
+		req.Header.Set("X-OTX-API-KEY", apiKey)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 429 {
+		return nil, fmt.Errorf("rate limit exceeded")
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var out otxResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	return &out, nil
+}
@@ -0,0 +1,126 @@
+package passivesources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"golang.org/x/time/rate"
+)
+
+// securityTrailsBaseURL is the SecurityTrails API root.
+const securityTrailsBaseURL = "https://api.securitytrails.com/v1"
+
+// securityTrailsLimiter caps requests at 2 per second, within the free-tier
+// rate limit documented for the SecurityTrails API. It is shared between the
+// subdomains lookup and the per-subdomain URL enumeration that follows it.
+var securityTrailsLimiter = rate.NewLimiter(2, 1)
+
+// securityTrailsSubdomainsResponse is the response shape of
+// GET /v1/domain/{domain}/subdomains.
+type securityTrailsSubdomainsResponse struct {
+	Subdomains []string `json:"subdomains"`
+}
+
+// SecurityTrailsSource enumerates subdomains for a domain via the
+// SecurityTrails API, then reports each subdomain's root URL as a candidate
+// for further passive URL enumeration. It requires an API key, read from the
+// SECURITYTRAILS_API_KEY environment variable.
+type SecurityTrailsSource struct{}
+
+// Name implements Source.
+func (SecurityTrailsSource) Name() string { return "securitytrails" }
+
+// Fetch implements Source.
+func (s SecurityTrailsSource) Fetch(ctx context.Context, domain string) <-chan URLInfo {
+	out := make(chan URLInfo)
+
+	go func() {
+		defer close(out)
+
+		apiKey := os.Getenv("SECURITYTRAILS_API_KEY")
+		if apiKey == "" {
+			fmt.Fprintln(os.Stderr, "securitytrails: SECURITYTRAILS_API_KEY not set, skipping")
+			return
+		}
+
+		if err := securityTrailsLimiter.Wait(ctx); err != nil {
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/domain/%s/subdomains", securityTrailsBaseURL, domain), nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "securitytrails: error building request: %v\n", err)
+			return
+		}
+		req.Header.Set("APIKEY", apiKey)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "securitytrails: error fetching subdomains for %s: %v\n", domain, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != 200 {
+			fmt.Fprintf(os.Stderr, "securitytrails: unexpected status code %d for %s\n", resp.StatusCode, domain)
+			return
+		}
+
+		var body securityTrailsSubdomainsResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			fmt.Fprintf(os.Stderr, "securitytrails: error parsing response: %v\n", err)
+			return
+		}
+
+		for _, sub := range body.Subdomains {
+			fqdn := fmt.Sprintf("%s.%s", sub, domain)
+			if err := securityTrailsLimiter.Wait(ctx); err != nil {
+				return
+			}
+			info, ok := enumerateSecurityTrailsURL(ctx, s.Name(), fqdn)
+			if !ok {
+				continue
+			}
+			select {
+			case out <- info:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// enumerateSecurityTrailsURL probes the guessed root URL for fqdn so the
+// reported record reflects whether a host is actually listening there,
+// rather than a synthesized URL that was never verified. It falls back to
+// http:// if https:// is unreachable. ok is false if neither scheme responds.
+func enumerateSecurityTrailsURL(ctx context.Context, source, fqdn string) (info URLInfo, ok bool) {
+	for _, scheme := range []string{"https", "http"} {
+		candidate := fmt.Sprintf("%s://%s/", scheme, fqdn)
+
+		req, err := http.NewRequestWithContext(ctx, "HEAD", candidate, nil)
+		if err != nil {
+			continue
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+
+		return URLInfo{
+			URL:       candidate,
+			Source:    source,
+			FirstSeen: resp.Header.Get("Last-Modified"),
+			HTTPCode:  resp.StatusCode,
+		}, true
+	}
+
+	return URLInfo{}, false
+}
@@ -0,0 +1,27 @@
+// Package passivesources defines a common interface for passive URL
+// discovery backends (OTX, Wayback Machine, CommonCrawl, SecurityTrails, ...)
+// so that callers such as the fetch_urls command can fan out to several of
+// them concurrently and merge their results.
+package passivesources
+
+import "context"
+
+// URLInfo represents a single URL observed by a passive source.
+type URLInfo struct {
+	URL       string // The full URL that was observed
+	Source    string // Name of the source that reported this URL
+	FirstSeen string // When the source first observed the URL, source-specific format
+	HTTPCode  int    // HTTP status code recorded at observation time, if known (0 if unknown)
+}
+
+// Source is a passive URL discovery backend. Fetch streams every URL it
+// finds for domain on the returned channel and closes it when done or when
+// ctx is canceled. Errors are logged by the implementation to stderr rather
+// than returned, since a single failed page or index should not abort the
+// rest of the scan.
+type Source interface {
+	// Name identifies the source, used in URLInfo.Source and log output.
+	Name() string
+	// Fetch streams discovered URLs for domain until exhausted or ctx is done.
+	Fetch(ctx context.Context, domain string) <-chan URLInfo
+}
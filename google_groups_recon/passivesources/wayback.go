@@ -0,0 +1,95 @@
+package passivesources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/time/rate"
+)
+
+// waybackCDXURL is the Wayback Machine's CDX Server API, queried with a
+// wildcard subdomain match so a single call covers the whole domain.
+const waybackCDXURL = "https://web.archive.org/cdx/search/cdx"
+
+// waybackLimiter caps CDX Server queries at 1 per second across every
+// domain a fetch_urls run scans, per archive.org's API courtesy guidelines.
+var waybackLimiter = rate.NewLimiter(1, 1)
+
+// WaybackSource fetches URLs the Wayback Machine has ever captured for a
+// domain (and its subdomains) via the CDX Server API.
+type WaybackSource struct{}
+
+// Name implements Source.
+func (WaybackSource) Name() string { return "wayback" }
+
+// Fetch implements Source.
+func (s WaybackSource) Fetch(ctx context.Context, domain string) <-chan URLInfo {
+	out := make(chan URLInfo)
+
+	go func() {
+		defer close(out)
+
+		query := url.Values{}
+		query.Set("url", fmt.Sprintf("*.%s/*", domain))
+		query.Set("output", "json")
+		query.Set("collapse", "urlkey")
+		query.Set("fl", "original,timestamp,statuscode")
+
+		if err := waybackLimiter.Wait(ctx); err != nil {
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", waybackCDXURL+"?"+query.Encode(), nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "wayback: error building request: %v\n", err)
+			return
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "wayback: error fetching %s: %v\n", domain, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != 200 {
+			fmt.Fprintf(os.Stderr, "wayback: unexpected status code %d for %s\n", resp.StatusCode, domain)
+			return
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "wayback: error reading response: %v\n", err)
+			return
+		}
+
+		// The CDX JSON format is an array of rows, the first of which is the
+		// column header rather than a data row.
+		var rows [][]string
+		if err := json.Unmarshal(body, &rows); err != nil {
+			fmt.Fprintf(os.Stderr, "wayback: error parsing response: %v\n", err)
+			return
+		}
+
+		for i, row := range rows {
+			if i == 0 || len(row) < 3 {
+				continue
+			}
+			httpCode := 0
+			fmt.Sscanf(row[2], "%d", &httpCode)
+
+			select {
+			case out <- URLInfo{URL: row[0], Source: s.Name(), FirstSeen: row[1], HTTPCode: httpCode}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,156 @@
+// recon orchestrates the google_groups_recon pipeline (expand_domains, then
+// fetch_urls, trim_google_group_urls, filter_google_group_domains, and
+// check_google_group_permissions) as a single resumable run backed by a
+// shared store database, instead of the user manually piping one tool's
+// stdout into the next.
+//
+// Each stage runs as its own sibling binary, invoked with -db so it reads
+// and writes through the store rather than stdin/stdout. recon checkpoints
+// every stage it completes, so a run interrupted partway through can be
+// restarted with the same command and will skip stages whose checkpoint is
+// still within -checkpoint-ttl of now (this scan); a checkpoint older than
+// that is treated as a previous scan and the stage reruns.
+//
+// With -max-age, the check stage only re-checks groups whose most recent
+// permissions snapshot is older than the given duration, turning repeated
+// invocations into an incremental scan that only pays for groups that are
+// actually due. After the check stage, recon compares each group's two most
+// recent snapshots and reports any that flipped between private and public.
+//
+// Usage:
+//
+//	./recon -db scan.sqlite -seed-domain nist.gov
+//	./recon -db scan.sqlite -max-age 24h   # re-run later, only re-checking stale groups
+//
+// recon expects the other pipeline binaries (expand_domains, fetch_urls,
+// trim_google_group_urls, filter_google_group_domains,
+// check_google_group_permissions) to be built alongside it, either on PATH
+// or in the same directory as the recon binary itself.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/spaceraccoon/vibe-sec-tools/google_groups_recon/store"
+)
+
+func main() {
+	dbPath := flag.String("db", "", "Path to recon store SQLite database (required)")
+	seedDomain := flag.String("seed-domain", "", "Domain to seed the scan with on first run")
+	sources := flag.String("sources", "otx,wayback,commoncrawl,securitytrails", "Comma-separated list of fetch_urls sources to enable")
+	maxAge := flag.Duration("max-age", 24*time.Hour, "Skip re-checking groups whose latest snapshot is newer than this")
+	checkpointTTL := flag.Duration("checkpoint-ttl", time.Hour, "How long a completed stage's checkpoint remains valid; restarting within this window resumes by skipping it, restarting after it reruns the stage as a new scan")
+	flag.Parse()
+
+	if *dbPath == "" {
+		fmt.Fprintf(os.Stderr, "Usage: %s -db scan.sqlite [-seed-domain example.com] [-max-age 24h]\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	db, err := store.Open(*dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if *seedDomain != "" {
+		if err := db.UpsertDomain(*seedDomain); err != nil {
+			fmt.Fprintf(os.Stderr, "Error seeding domain %s: %v\n", *seedDomain, err)
+			os.Exit(1)
+		}
+	}
+
+	stages := []struct {
+		name string
+		args []string
+	}{
+		{"expand", []string{"expand_domains", "-db", *dbPath}},
+		{"fetch", []string{"fetch_urls", "-db", *dbPath, "-sources", *sources}},
+		{"trim", []string{"trim_google_group_urls", "-trim", "-db", *dbPath}},
+		{"filter", []string{"filter_google_group_domains", "-db", *dbPath}},
+		{"check", []string{"check_google_group_permissions", "-db", *dbPath, "-max-age", maxAge.String()}},
+	}
+
+	for _, stage := range stages {
+		completedAt, err := db.CheckpointedAt(stage.name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "recon: error reading checkpoint for stage %q: %v\n", stage.name, err)
+			os.Exit(1)
+		}
+		if !completedAt.IsZero() && time.Since(completedAt) < *checkpointTTL {
+			fmt.Fprintf(os.Stderr, "recon: skipping stage %q, checkpointed %v ago\n", stage.name, time.Since(completedAt).Round(time.Second))
+			continue
+		}
+
+		fmt.Fprintf(os.Stderr, "recon: running stage %q\n", stage.name)
+		if err := runTool(stage.args[0], stage.args[1:]...); err != nil {
+			fmt.Fprintf(os.Stderr, "recon: stage %q failed: %v\n", stage.name, err)
+			os.Exit(1)
+		}
+		if err := db.Checkpoint(stage.name); err != nil {
+			fmt.Fprintf(os.Stderr, "recon: error checkpointing stage %q: %v\n", stage.name, err)
+		}
+	}
+
+	if err := reportPermissionFlips(db); err != nil {
+		fmt.Fprintf(os.Stderr, "recon: error reporting permission changes: %v\n", err)
+	}
+}
+
+// runTool runs a sibling pipeline binary, looking it up next to the recon
+// binary first and falling back to PATH.
+func runTool(name string, args ...string) error {
+	path := name
+	if self, err := os.Executable(); err == nil {
+		if candidate := filepath.Join(filepath.Dir(self), name); fileExists(candidate) {
+			path = candidate
+		}
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// reportPermissionFlips compares each group's two most recent snapshots and
+// prints the ones that changed whether the group is publicly accessible.
+func reportPermissionFlips(db *store.Store) error {
+	groups, err := db.Groups()
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		latest, err := db.LatestSnapshot(group.Email)
+		if err != nil {
+			return err
+		}
+		previous, err := db.PreviousSnapshot(group.Email)
+		if err != nil {
+			return err
+		}
+		if latest == nil || previous == nil || latest.IsPublic == previous.IsPublic {
+			continue
+		}
+
+		direction := "public -> private"
+		if latest.IsPublic {
+			direction = "private -> public"
+		}
+		fmt.Printf("%s flipped %s (was %s, now %s)\n", group.Email, direction, previous.SeenAt.Format(time.RFC3339), latest.SeenAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
@@ -0,0 +1,289 @@
+// Package store provides a SQLite-backed persistence layer shared by the
+// google_groups_recon tools. It lets each stage of the pipeline
+// (fetch_urls, trim_google_group_urls, filter_google_group_domains,
+// check_google_group_permissions) read its input from and write its output
+// to a common database via a -db flag, instead of relying solely on
+// stdin/stdout piping, so that a scan's state survives between runs.
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// schema creates every table used by the pipeline if it does not already
+// exist. permissions_snapshots keeps one row per check rather than
+// overwriting in place, so callers can detect when a group's permissions
+// change between scans.
+const schema = `
+CREATE TABLE IF NOT EXISTS domains (
+	domain TEXT PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS urls (
+	url TEXT PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS groups (
+	email  TEXT PRIMARY KEY,
+	domain TEXT NOT NULL,
+	url    TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS permissions_snapshots (
+	group_email TEXT NOT NULL,
+	seen_at     DATETIME NOT NULL,
+	is_public   BOOLEAN NOT NULL,
+	can_view    BOOLEAN NOT NULL,
+	can_post    BOOLEAN NOT NULL,
+	can_join    BOOLEAN NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_permissions_snapshots_group_seen_at
+	ON permissions_snapshots (group_email, seen_at DESC);
+
+CREATE TABLE IF NOT EXISTS checkpoints (
+	stage        TEXT PRIMARY KEY,
+	completed_at DATETIME NOT NULL
+);
+`
+
+// Store wraps a SQLite database holding recon pipeline state.
+type Store struct {
+	db *sql.DB
+}
+
+// Group identifies a Google Group discovered during a scan.
+type Group struct {
+	Email  string
+	Domain string
+	URL    string
+}
+
+// Snapshot is a single point-in-time read of a group's permissions.
+type Snapshot struct {
+	SeenAt   time.Time
+	IsPublic bool
+	CanView  bool
+	CanPost  bool
+	CanJoin  bool
+}
+
+// Open opens (creating if necessary) the SQLite database at path and
+// ensures the pipeline schema is present.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("applying schema: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// UpsertDomain records a discovered domain.
+func (s *Store) UpsertDomain(domain string) error {
+	_, err := s.db.Exec(`INSERT OR IGNORE INTO domains (domain) VALUES (?)`, domain)
+	return err
+}
+
+// Domains returns every domain recorded so far.
+func (s *Store) Domains() ([]string, error) {
+	rows, err := s.db.Query(`SELECT domain FROM domains ORDER BY domain`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var domains []string
+	for rows.Next() {
+		var domain string
+		if err := rows.Scan(&domain); err != nil {
+			return nil, err
+		}
+		domains = append(domains, domain)
+	}
+	return domains, rows.Err()
+}
+
+// UpsertURL records a discovered URL.
+func (s *Store) UpsertURL(url string) error {
+	_, err := s.db.Exec(`INSERT OR IGNORE INTO urls (url) VALUES (?)`, url)
+	return err
+}
+
+// ReplaceURLs atomically replaces the contents of the urls table with urls.
+// It is used by stages such as trim_google_group_urls that normalize the
+// existing set of URLs in place rather than adding new ones.
+func (s *Store) ReplaceURLs(urls []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM urls`); err != nil {
+		return err
+	}
+	for _, url := range urls {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO urls (url) VALUES (?)`, url); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// URLs returns every URL recorded so far.
+func (s *Store) URLs() ([]string, error) {
+	rows, err := s.db.Query(`SELECT url FROM urls ORDER BY url`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+	return urls, rows.Err()
+}
+
+// UpsertGroup records a discovered group, keyed by its email address.
+func (s *Store) UpsertGroup(group Group) error {
+	_, err := s.db.Exec(
+		`INSERT INTO groups (email, domain, url) VALUES (?, ?, ?)
+		 ON CONFLICT(email) DO UPDATE SET domain = excluded.domain, url = excluded.url`,
+		group.Email, group.Domain, group.URL,
+	)
+	return err
+}
+
+// Groups returns every group recorded so far.
+func (s *Store) Groups() ([]Group, error) {
+	rows, err := s.db.Query(`SELECT email, domain, url FROM groups ORDER BY email`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []Group
+	for rows.Next() {
+		var g Group
+		if err := rows.Scan(&g.Email, &g.Domain, &g.URL); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+// GroupsNeedingCheck returns the groups that have never been snapshotted, or
+// whose most recent snapshot is older than maxAge — the set an incremental
+// scan should re-check.
+func (s *Store) GroupsNeedingCheck(maxAge time.Duration) ([]Group, error) {
+	groups, err := s.Groups()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	var stale []Group
+	for _, g := range groups {
+		snapshot, err := s.LatestSnapshot(g.Email)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot == nil || snapshot.SeenAt.Before(cutoff) {
+			stale = append(stale, g)
+		}
+	}
+	return stale, nil
+}
+
+// RecordSnapshot appends a new permissions snapshot for groupEmail.
+// Snapshots are append-only so PreviousSnapshot can detect changes.
+func (s *Store) RecordSnapshot(groupEmail string, snapshot Snapshot) error {
+	_, err := s.db.Exec(
+		`INSERT INTO permissions_snapshots (group_email, seen_at, is_public, can_view, can_post, can_join)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		groupEmail, snapshot.SeenAt, snapshot.IsPublic, snapshot.CanView, snapshot.CanPost, snapshot.CanJoin,
+	)
+	return err
+}
+
+// LatestSnapshot returns the most recent permissions snapshot for
+// groupEmail, or nil if the group has never been checked.
+func (s *Store) LatestSnapshot(groupEmail string) (*Snapshot, error) {
+	return s.nthSnapshot(groupEmail, 0)
+}
+
+// PreviousSnapshot returns the second most recent permissions snapshot for
+// groupEmail, or nil if fewer than two snapshots exist. Comparing it against
+// LatestSnapshot is how callers detect a group flipping from private to
+// public (or vice versa).
+func (s *Store) PreviousSnapshot(groupEmail string) (*Snapshot, error) {
+	return s.nthSnapshot(groupEmail, 1)
+}
+
+func (s *Store) nthSnapshot(groupEmail string, offset int) (*Snapshot, error) {
+	row := s.db.QueryRow(
+		`SELECT seen_at, is_public, can_view, can_post, can_join
+		 FROM permissions_snapshots
+		 WHERE group_email = ?
+		 ORDER BY seen_at DESC
+		 LIMIT 1 OFFSET ?`,
+		groupEmail, offset,
+	)
+
+	var snapshot Snapshot
+	err := row.Scan(&snapshot.SeenAt, &snapshot.IsPublic, &snapshot.CanView, &snapshot.CanPost, &snapshot.CanJoin)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// Checkpoint marks stage as completed at the current time.
+func (s *Store) Checkpoint(stage string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO checkpoints (stage, completed_at) VALUES (?, CURRENT_TIMESTAMP)
+		 ON CONFLICT(stage) DO UPDATE SET completed_at = excluded.completed_at`,
+		stage,
+	)
+	return err
+}
+
+// CheckpointedAt returns when stage last completed, or the zero time if it
+// has never run.
+func (s *Store) CheckpointedAt(stage string) (time.Time, error) {
+	row := s.db.QueryRow(`SELECT completed_at FROM checkpoints WHERE stage = ?`, stage)
+
+	var completedAt time.Time
+	err := row.Scan(&completedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return completedAt, nil
+}
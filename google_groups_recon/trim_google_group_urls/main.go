@@ -24,6 +24,9 @@
 // Example output (with -trim):
 //
 //	https://groups.google.com/a/example.com/g/team
+//
+// With -db path.sqlite, the tool reads and normalizes the shared recon
+// store's urls table in place instead of piping through stdin/stdout.
 package main
 
 import (
@@ -32,40 +35,80 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+
+	"github.com/spaceraccoon/vibe-sec-tools/google_groups_recon/store"
 )
 
 func main() {
 	trim := flag.Bool("trim", false, "Trim URLs to base group URL format and deduplicate")
+	dbPath := flag.String("db", "", "Path to recon store SQLite database (reads/writes through it instead of stdin/stdout)")
 	flag.Parse()
 
 	// Pattern for Google Workspace groups with custom domains.
 	// Example: https://groups.google.com/a/list.nist.gov/g/internet-time-service
 	pattern := regexp.MustCompile(`https?://groups\.google\.com/a/[^/]+/g/[^/]+`)
 
-	scanner := bufio.NewScanner(os.Stdin)
+	var db *store.Store
+	if *dbPath != "" {
+		var err error
+		db, err = store.Open(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening store: %v\n", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+	}
+
+	input, err := inputURLs(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+
+	var output []string
 	seen := make(map[string]bool)
-	
-	for scanner.Scan() {
-		url := scanner.Text()
-		
+
+	for _, url := range input {
 		if *trim {
-			// Extract and trim to base URL
 			match := pattern.FindString(url)
-			if match != "" && !seen[match] {
-				seen[match] = true
-				fmt.Println(match)
+			if match == "" || seen[match] {
+				continue
 			}
+			seen[match] = true
+			output = append(output, match)
 		} else {
-			// Original behavior: match full URL
-			if pattern.MatchString(url) && !seen[url] {
-				seen[url] = true
-				fmt.Println(url)
+			if !pattern.MatchString(url) || seen[url] {
+				continue
 			}
+			seen[url] = true
+			output = append(output, url)
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
-		os.Exit(1)
+	if db != nil {
+		if err := db.ReplaceURLs(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing urls: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	for _, url := range output {
+		fmt.Println(url)
+	}
+}
+
+// inputURLs returns the URLs to process, reading from the store if db is
+// non-nil or from stdin otherwise.
+func inputURLs(db *store.Store) ([]string, error) {
+	if db != nil {
+		return db.URLs()
+	}
+
+	var urls []string
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		urls = append(urls, scanner.Text())
 	}
+	return urls, scanner.Err()
 }